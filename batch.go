@@ -0,0 +1,364 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// probeConfig é a representação em ficheiro de um único probe do modo batch,
+// aceitando os mesmos campos disponíveis como flags na linha de comandos.
+type probeConfig struct {
+	Name               string   `json:"name"`
+	URL                string   `json:"url"`
+	Method             string   `json:"method"`
+	AcceptedCodes      string   `json:"accepted_codes"`
+	BodyContains       string   `json:"body_contains"`
+	BodyRegex          string   `json:"body_regex"`
+	BodyJSONPath       string   `json:"body_jsonpath"`
+	HeaderEquals       []string `json:"header_equals"`
+	HeaderRegex        []string `json:"header_regex"`
+	Headers            []string `json:"headers"`
+	ContentType        string   `json:"content_type"`
+	Data               string   `json:"data"`
+	BasicAuth          string   `json:"basic_auth"`
+	TimeoutSeconds     *int     `json:"timeout"`
+	InsecureSkipVerify bool     `json:"insecure"`
+	CACert             string   `json:"cacert"`
+	CACertEnvName      string   `json:"cacert_env_name"`
+	Cert               string   `json:"cert"`
+	CertEnvName        string   `json:"cert_env_name"`
+	Key                string   `json:"key"`
+	KeyEnvName         string   `json:"key_env_name"`
+	ServerName         string   `json:"servername"`
+	Proxy              string   `json:"proxy"`
+	FollowRedirects    string   `json:"follow_redirects"`
+	MaxRedirects       *int     `json:"max_redirects"`
+	AcceptRedirect     bool     `json:"accept_redirect"`
+	Retries            int      `json:"retries"`
+	RetryDelayMs       *int     `json:"retry_delay"`
+	RetryMaxDelayMs    *int     `json:"retry_max_delay"`
+	RetryOn            string   `json:"retry_on"`
+	DeadlineSeconds    int      `json:"deadline"`
+}
+
+// batchFile é o documento de topo de um ficheiro -f/--config.
+type batchFile struct {
+	Probes []probeConfig `json:"probes"`
+}
+
+// loadBatchFile lê e interpreta o ficheiro de configuração do modo batch.
+// Apenas JSON é suportado por agora; o formato é ainda assim anunciado como
+// ".yaml|.json" para deixar claro que é aqui que entraria um parser YAML,
+// sem depender de uma biblioteca externa que este módulo não tem disponível.
+func loadBatchFile(path string) ([]probeConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler o ficheiro de configuração '%s': %w", path, err)
+	}
+
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == ".yaml" || ext == ".yml" {
+		return nil, fmt.Errorf("formato YAML ainda não é suportado em '%s'; use um ficheiro .json", path)
+	}
+
+	var file batchFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("erro ao interpretar o ficheiro de configuração '%s': %w", path, err)
+	}
+	if len(file.Probes) == 0 {
+		return nil, fmt.Errorf("o ficheiro de configuração '%s' não define nenhum probe", path)
+	}
+	return file.Probes, nil
+}
+
+// toProbe resolve um probeConfig do ficheiro de configuração para um Probe
+// executável, aplicando os mesmos valores por omissão usados pelas flags.
+func (c probeConfig) toProbe() (*Probe, error) {
+	method := strings.ToUpper(c.Method)
+	if method == "" {
+		method = "GET"
+	}
+
+	timeoutSeconds := 5
+	if c.TimeoutSeconds != nil {
+		timeoutSeconds = *c.TimeoutSeconds
+	}
+
+	followRedirects := c.FollowRedirects
+	if followRedirects == "" {
+		followRedirects = "auto"
+	}
+
+	maxRedirects := 10
+	if c.MaxRedirects != nil {
+		maxRedirects = *c.MaxRedirects
+	}
+
+	retryDelayMs := 200
+	if c.RetryDelayMs != nil {
+		retryDelayMs = *c.RetryDelayMs
+	}
+	retryMaxDelayMs := 2000
+	if c.RetryMaxDelayMs != nil {
+		retryMaxDelayMs = *c.RetryMaxDelayMs
+	}
+	retryOn := c.RetryOn
+	if retryOn == "" {
+		retryOn = "5xx,connect,timeout"
+	}
+
+	var acceptedCodeMap map[int]bool
+	if c.AcceptedCodes != "" {
+		acceptedCodeMap = make(map[int]bool)
+		for _, codeStr := range strings.Split(c.AcceptedCodes, ",") {
+			code, err := strconv.Atoi(strings.TrimSpace(codeStr))
+			if err != nil {
+				return nil, fmt.Errorf("probe '%s': accepted_codes inválido: %v", c.Name, err)
+			}
+			acceptedCodeMap[code] = true
+		}
+	}
+
+	var hasBasicAuth bool
+	var basicAuthUser, basicAuthPass string
+	if c.BasicAuth != "" {
+		user, pass, found := strings.Cut(c.BasicAuth, ":")
+		if !found {
+			return nil, fmt.Errorf("probe '%s': basic_auth precisa do formato user:pass", c.Name)
+		}
+		hasBasicAuth = true
+		basicAuthUser, basicAuthPass = user, pass
+	}
+
+	var cacertPEM, certPEM, keyPEM []byte
+	if c.CACertEnvName != "" {
+		cacertPEM = []byte(os.Getenv(c.CACertEnvName))
+	} else if c.CACert != "" {
+		pemBytes, err := os.ReadFile(c.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("probe '%s': erro ao ler cacert '%s': %w", c.Name, c.CACert, err)
+		}
+		cacertPEM = pemBytes
+	}
+	if c.CertEnvName != "" {
+		certPEM = []byte(os.Getenv(c.CertEnvName))
+	} else if c.Cert != "" {
+		pemBytes, err := os.ReadFile(c.Cert)
+		if err != nil {
+			return nil, fmt.Errorf("probe '%s': erro ao ler cert '%s': %w", c.Name, c.Cert, err)
+		}
+		certPEM = pemBytes
+	}
+	if c.KeyEnvName != "" {
+		keyPEM = []byte(os.Getenv(c.KeyEnvName))
+	} else if c.Key != "" {
+		pemBytes, err := os.ReadFile(c.Key)
+		if err != nil {
+			return nil, fmt.Errorf("probe '%s': erro ao ler key '%s': %w", c.Name, c.Key, err)
+		}
+		keyPEM = pemBytes
+	}
+
+	return &Probe{
+		Name:                 c.Name,
+		URL:                  c.URL,
+		Method:               method,
+		Timeout:              time.Duration(timeoutSeconds) * time.Second,
+		AcceptedCodeMap:      acceptedCodeMap,
+		AcceptedCodesDisplay: c.AcceptedCodes,
+		BodyContains:         c.BodyContains,
+		BodyRegex:            c.BodyRegex,
+		BodyJSONPath:         c.BodyJSONPath,
+		HeaderEquals:         c.HeaderEquals,
+		HeaderRegex:          c.HeaderRegex,
+		Headers:              c.Headers,
+		ContentType:          c.ContentType,
+		Body:                 []byte(c.Data),
+		HasBasicAuth:         hasBasicAuth,
+		BasicAuthUser:        basicAuthUser,
+		BasicAuthPass:        basicAuthPass,
+		InsecureSkipVerify:   c.InsecureSkipVerify,
+		CACertPEM:            cacertPEM,
+		CertPEM:              certPEM,
+		KeyPEM:               keyPEM,
+		ServerName:           c.ServerName,
+		ProxyURL:             c.Proxy,
+		FollowRedirects:      followRedirects,
+		MaxRedirects:         maxRedirects,
+		AcceptRedirect:       c.AcceptRedirect,
+		Retries:              c.Retries,
+		RetryDelayMs:         retryDelayMs,
+		RetryMaxDelayMs:      retryMaxDelayMs,
+		RetryOn:              retryOn,
+		DeadlineSeconds:      c.DeadlineSeconds,
+	}, nil
+}
+
+// evaluateBatchMode decide se o conjunto de resultados satisfaz o critério
+// de sucesso global pedido via --mode (all, any ou quorum:N).
+func evaluateBatchMode(mode string, results []Result) (bool, error) {
+	successCount := 0
+	for _, r := range results {
+		if r.Success {
+			successCount++
+		}
+	}
+
+	switch {
+	case mode == "all":
+		return successCount == len(results), nil
+	case mode == "any":
+		return successCount > 0, nil
+	case strings.HasPrefix(mode, "quorum:"):
+		n, err := strconv.Atoi(strings.TrimPrefix(mode, "quorum:"))
+		if err != nil {
+			return false, fmt.Errorf("--mode quorum inválido: %s", mode)
+		}
+		return successCount >= n, nil
+	default:
+		return false, fmt.Errorf("--mode inválido: %s. Use all, any ou quorum:N", mode)
+	}
+}
+
+// printBatchSummaryHuman imprime um resumo legível dos resultados do modo batch.
+func printBatchSummaryHuman(results []Result, mode string, overallSuccess bool) {
+	for _, r := range results {
+		status := "FALHOU"
+		if r.Success {
+			status = "OK"
+		}
+		fmt.Printf("[%s] %s %s -> %s (%d) em %s", status, r.Method, r.URL, r.FinalURL, r.StatusCode, r.Latency)
+		if r.Error != "" {
+			fmt.Printf(" | erro: %s", r.Error)
+		}
+		fmt.Println()
+	}
+
+	successCount := 0
+	for _, r := range results {
+		if r.Success {
+			successCount++
+		}
+	}
+	fmt.Printf("\nResumo: %d/%d probes com sucesso (modo: %s)\n", successCount, len(results), mode)
+	if overallSuccess {
+		fmt.Println("Resultado geral: sucesso")
+	} else {
+		fmt.Println("Resultado geral: falha")
+	}
+}
+
+// printBatchSummaryJSON imprime os resultados do modo batch em JSON.
+func printBatchSummaryJSON(results []Result, mode string, overallSuccess bool) {
+	type jsonResult struct {
+		Name       string `json:"name"`
+		URL        string `json:"url"`
+		Method     string `json:"method"`
+		Success    bool   `json:"success"`
+		StatusCode int    `json:"status_code"`
+		FinalURL   string `json:"final_url"`
+		LatencyMs  int64  `json:"latency_ms"`
+		Error      string `json:"error,omitempty"`
+	}
+
+	out := struct {
+		Mode    string       `json:"mode"`
+		Success bool         `json:"success"`
+		Probes  []jsonResult `json:"probes"`
+	}{
+		Mode:    mode,
+		Success: overallSuccess,
+	}
+
+	for _, r := range results {
+		out.Probes = append(out.Probes, jsonResult{
+			Name:       r.Name,
+			URL:        r.URL,
+			Method:     r.Method,
+			Success:    r.Success,
+			StatusCode: r.StatusCode,
+			FinalURL:   r.FinalURL,
+			LatencyMs:  r.Latency.Milliseconds(),
+			Error:      r.Error,
+		})
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(out); err != nil {
+		fmt.Fprintf(os.Stderr, "Erro ao gerar a saída JSON: %v\n", err)
+	}
+}
+
+// runBatchMode carrega o ficheiro de configuração, executa todos os probes
+// concorrentemente através de um pool de workers limitado a --concurrency,
+// imprime o resumo no formato pedido e termina o processo com o código de
+// saída correspondente ao critério de sucesso em --mode.
+func runBatchMode(configFile string, concurrency int, mode string, outputFormat string, verbose bool) {
+	configs, err := loadBatchFile(configFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Erro: %v\n", err)
+		os.Exit(1)
+	}
+
+	probes := make([]*Probe, len(configs))
+	for i, c := range configs {
+		probe, err := c.toProbe()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Erro: %v\n", err)
+			os.Exit(1)
+		}
+		probe.Verbose = verbose
+		probes[i] = probe
+	}
+
+	if concurrency <= 0 {
+		concurrency = len(probes)
+		if concurrency > 8 {
+			concurrency = 8
+		}
+	}
+
+	results := make([]Result, len(probes))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = probes[i].Run(context.Background())
+			}
+		}()
+	}
+	for i := range probes {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	overallSuccess, err := evaluateBatchMode(mode, results)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Erro: %v\n", err)
+		os.Exit(1)
+	}
+
+	if outputFormat == "json" {
+		printBatchSummaryJSON(results, mode, overallSuccess)
+	} else {
+		printBatchSummaryHuman(results, mode, overallSuccess)
+	}
+
+	if overallSuccess {
+		os.Exit(0)
+	}
+	os.Exit(1)
+}
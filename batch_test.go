@@ -0,0 +1,48 @@
+package main
+
+import "testing"
+
+func TestEvaluateBatchMode(t *testing.T) {
+	results := func(successes ...bool) []Result {
+		rs := make([]Result, len(successes))
+		for i, s := range successes {
+			rs[i] = Result{Success: s}
+		}
+		return rs
+	}
+
+	cases := []struct {
+		name    string
+		mode    string
+		results []Result
+		want    bool
+		wantErr bool
+	}{
+		{"all com todos a passar", "all", results(true, true, true), true, false},
+		{"all com uma falha", "all", results(true, false, true), false, false},
+		{"any com uma a passar", "any", results(false, false, true), true, false},
+		{"any sem nenhuma a passar", "any", results(false, false), false, false},
+		{"quorum satisfeito", "quorum:2", results(true, true, false), true, false},
+		{"quorum não satisfeito", "quorum:2", results(true, false, false), false, false},
+		{"modo inválido", "bogus", results(true), false, true},
+		{"quorum com N inválido", "quorum:abc", results(true), false, true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := evaluateBatchMode(c.mode, c.results)
+			if c.wantErr {
+				if err == nil {
+					t.Errorf("evaluateBatchMode(%q) esperava erro, obteve sucesso=%v", c.mode, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("evaluateBatchMode(%q) erro inesperado: %v", c.mode, err)
+			}
+			if got != c.want {
+				t.Errorf("evaluateBatchMode(%q) = %v, esperado %v", c.mode, got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,539 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	neturl "net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Probe descreve um único pedido de verificação HTTP totalmente resolvido
+// (sem variáveis de ambiente por resolver), usado tanto pelo modo de
+// invocação única (flags na linha de comandos) como pelo modo batch
+// (-f/--config).
+type Probe struct {
+	Name    string
+	URL     string
+	Method  string
+	Timeout time.Duration
+	Verbose bool
+
+	AcceptedCodeMap      map[int]bool
+	AcceptedCodesDisplay string
+
+	BodyContains string
+	BodyRegex    string
+	BodyJSONPath string
+	HeaderEquals []string
+	HeaderRegex  []string
+
+	Headers       []string
+	ContentType   string
+	Body          []byte
+	HasBasicAuth  bool
+	BasicAuthUser string
+	BasicAuthPass string
+
+	InsecureSkipVerify bool
+	CACertPEM          []byte
+	CertPEM            []byte
+	KeyPEM             []byte
+	ServerName         string
+
+	ProxyURL string
+
+	FollowRedirects string
+	MaxRedirects    int
+	AcceptRedirect  bool
+
+	Retries         int
+	RetryDelayMs    int
+	RetryMaxDelayMs int
+	RetryOn         string
+	DeadlineSeconds int
+}
+
+// Result é o resultado de uma execução de Probe.Run, adequado tanto para
+// impressão direta (modo único) como para agregação (modo batch).
+type Result struct {
+	Name       string
+	URL        string
+	Method     string
+	Success    bool
+	StatusCode int
+	FinalURL   string
+	Latency    time.Duration
+	Error      string
+}
+
+// logf imprime uma mensagem verbose, prefixada com o nome da probe quando
+// esta tem um (caso do modo batch).
+func (p *Probe) logf(w io.Writer, format string, args ...interface{}) {
+	if !p.Verbose {
+		return
+	}
+	if p.Name != "" {
+		fmt.Fprintf(w, "[%s] ", p.Name)
+	}
+	fmt.Fprintf(w, format, args...)
+}
+
+// Run executa o pedido (com proxy, TLS, redirecionamentos e retries conforme
+// configurado) e devolve o resultado da verificação, sem nunca chamar
+// os.Exit — quem decide a saída do processo é o chamador.
+func (p *Probe) Run(ctx context.Context) Result {
+	start := time.Now()
+	result := Result{Name: p.Name, URL: p.URL, Method: p.Method}
+
+	client := &http.Client{
+		Timeout: p.Timeout,
+	}
+
+	transport := &http.Transport{}
+	tlsConfig := &tls.Config{}
+
+	if p.InsecureSkipVerify {
+		tlsConfig.InsecureSkipVerify = true
+		p.logf(os.Stdout, "Aviso: Conexões TLS inseguras estão habilitadas. Use com cautela.\n")
+	}
+
+	if p.ServerName != "" {
+		tlsConfig.ServerName = p.ServerName
+	}
+
+	if len(p.CACertPEM) > 0 {
+		caPool := x509.NewCertPool()
+		if !caPool.AppendCertsFromPEM(p.CACertPEM) {
+			result.Error = "não foi possível interpretar o PEM da CA fornecida"
+			result.Latency = time.Since(start)
+			return result
+		}
+		tlsConfig.RootCAs = caPool
+		p.logf(os.Stdout, "Usando CA personalizada em vez das raízes do sistema.\n")
+	}
+
+	if len(p.CertPEM) > 0 || len(p.KeyPEM) > 0 {
+		clientCert, err := tls.X509KeyPair(p.CertPEM, p.KeyPEM)
+		if err != nil {
+			result.Error = fmt.Sprintf("erro ao carregar o par certificado/chave do cliente: %v", err)
+			result.Latency = time.Since(start)
+			return result
+		}
+		tlsConfig.Certificates = []tls.Certificate{clientCert}
+		p.logf(os.Stdout, "Usando certificado de cliente para autenticação mTLS.\n")
+	}
+
+	transport.TLSClientConfig = tlsConfig
+
+	if p.ProxyURL != "" {
+		proxyURL, err := neturl.Parse(p.ProxyURL)
+		if err != nil {
+			result.Error = fmt.Sprintf("erro ao analisar o URL do proxy '%s': %v", p.ProxyURL, err)
+			result.Latency = time.Since(start)
+			return result
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+		p.logf(os.Stdout, "Usando proxy explícito: %s\n", p.ProxyURL)
+	} else {
+		// Sem proxy explícito, respeita HTTP_PROXY/HTTPS_PROXY/NO_PROXY do ambiente.
+		// Para um alvo https com proxy, o próprio http.Transport emite um CONNECT
+		// ao proxy e faz o handshake TLS com a origem através do túnel.
+		transport.Proxy = http.ProxyFromEnvironment
+	}
+
+	client.Transport = transport
+
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if req.Response != nil {
+			p.logf(os.Stdout, "Redirecionamento: %s %d -> %s\n", req.Response.Request.Method, req.Response.StatusCode, req.Response.Header.Get("Location"))
+		}
+		switch p.FollowRedirects {
+		case "never":
+			return http.ErrUseLastResponse
+		case "same-host":
+			if req.URL.Host != via[0].URL.Host {
+				return fmt.Errorf("redirecionamento para um host diferente recusado (política same-host): %s -> %s", via[0].URL.Host, req.URL.Host)
+			}
+			if len(via) >= p.MaxRedirects {
+				return fmt.Errorf("demasiados redirecionamentos (máximo %d)", p.MaxRedirects)
+			}
+			return nil
+		default: // "auto"
+			if len(via) >= p.MaxRedirects {
+				return fmt.Errorf("demasiados redirecionamentos (máximo %d)", p.MaxRedirects)
+			}
+			return nil
+		}
+	}
+
+	retryOnCodes, retry5xx, retryOnConnect, retryOnTimeout, err := parseRetryOn(p.RetryOn)
+	if err != nil {
+		result.Error = err.Error()
+		result.Latency = time.Since(start)
+		return result
+	}
+
+	// timeoutSeconds/deadlineSeconds <= 0 significa "sem limite", tal como
+	// http.Client{Timeout: 0} — não deve ser coagido para um orçamento de 0s.
+	deadlineSeconds := p.DeadlineSeconds
+	if deadlineSeconds <= 0 && p.Timeout > 0 {
+		deadlineSeconds = int(p.Timeout/time.Second) * (p.Retries + 1)
+	}
+
+	reqCtx := ctx
+	cancel := func() {}
+	if deadlineSeconds > 0 {
+		reqCtx, cancel = context.WithTimeout(ctx, time.Duration(deadlineSeconds)*time.Second)
+	}
+	defer cancel()
+
+	var resp *http.Response
+
+	for attempt := 0; ; attempt++ {
+		var bodyReader io.Reader
+		if len(p.Body) > 0 {
+			bodyReader = bytes.NewReader(p.Body)
+		}
+
+		req, reqErr := http.NewRequestWithContext(reqCtx, p.Method, p.URL, bodyReader)
+		if reqErr != nil {
+			result.Error = fmt.Sprintf("erro ao construir o pedido para %s (%s): %v", p.URL, p.Method, reqErr)
+			result.Latency = time.Since(start)
+			return result
+		}
+
+		for _, h := range p.Headers {
+			name, value, found := strings.Cut(h, ":")
+			if !found {
+				result.Error = fmt.Sprintf("-H/--header precisa do formato \"Nome: Valor\": %s", h)
+				result.Latency = time.Since(start)
+				return result
+			}
+			req.Header.Set(strings.TrimSpace(name), strings.TrimSpace(value))
+		}
+		if p.ContentType != "" {
+			req.Header.Set("Content-Type", p.ContentType)
+		}
+		if p.HasBasicAuth {
+			req.SetBasicAuth(p.BasicAuthUser, p.BasicAuthPass)
+		}
+
+		resp, err = client.Do(req)
+
+		shouldRetry := false
+		if err != nil {
+			if (retryOnConnect && isConnectError(err)) || (retryOnTimeout && isTimeoutError(err)) {
+				shouldRetry = true
+			}
+		} else if retry5xx && resp.StatusCode >= 500 && resp.StatusCode <= 599 {
+			shouldRetry = true
+		} else if retryOnCodes[resp.StatusCode] {
+			shouldRetry = true
+		}
+
+		if !shouldRetry || attempt >= p.Retries {
+			break
+		}
+
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		delay := backoffDelay(attempt, p.RetryDelayMs, p.RetryMaxDelayMs)
+		p.logf(os.Stdout, "Tentativa %d falhou (%v), a aguardar %dms antes de repetir\n", attempt+1, attemptOutcome(resp, err), delay)
+
+		select {
+		case <-time.After(time.Duration(delay) * time.Millisecond):
+		case <-reqCtx.Done():
+		}
+		if reqCtx.Err() != nil {
+			err = reqCtx.Err()
+			break
+		}
+	}
+
+	result.Latency = time.Since(start)
+
+	if err != nil {
+		result.Error = err.Error()
+		p.logf(os.Stderr, "Erro ao fazer o pedido para %s (%s): %v\n", p.URL, p.Method, err)
+		return result
+	}
+	defer resp.Body.Close()
+
+	result.StatusCode = resp.StatusCode
+	result.FinalURL = resp.Request.URL.String()
+
+	if p.AcceptRedirect && resp.StatusCode >= 300 && resp.StatusCode < 400 {
+		p.logf(os.Stdout, "Pedido bem-sucedido para %s (%s). Código de estado: %d (redirecionamento aceite via --accept-redirect)\n", p.URL, p.Method, resp.StatusCode)
+		result.Success = true
+		return result
+	}
+
+	hasBodyAssertion := p.BodyContains != "" || p.BodyRegex != "" || p.BodyJSONPath != ""
+	hasHeaderAssertion := len(p.HeaderEquals) > 0 || len(p.HeaderRegex) > 0
+	hasAssertion := hasBodyAssertion || hasHeaderAssertion
+	assertionsPassed := true
+
+	// Ler o corpo uma única vez se alguma asserção de corpo foi pedida (HEAD não tem corpo)
+	if hasBodyAssertion && p.Method != "HEAD" {
+		bodyBytes, readErr := io.ReadAll(resp.Body)
+		if readErr != nil {
+			result.Error = fmt.Sprintf("erro ao ler o corpo da resposta: %v", readErr)
+			return result
+		}
+		body := string(bodyBytes)
+
+		if p.BodyContains != "" {
+			if !strings.Contains(body, p.BodyContains) {
+				p.logf(os.Stderr, "Erro: O corpo da resposta não contém '%s'\n", p.BodyContains)
+				assertionsPassed = false
+			} else {
+				p.logf(os.Stdout, "Sucesso: O corpo da resposta contém '%s'\n", p.BodyContains)
+			}
+		}
+
+		if p.BodyRegex != "" {
+			bodyRegex, compileErr := regexp.Compile(p.BodyRegex)
+			if compileErr != nil {
+				result.Error = fmt.Sprintf("--body-regex inválido '%s': %v", p.BodyRegex, compileErr)
+				return result
+			}
+			if !bodyRegex.MatchString(body) {
+				p.logf(os.Stderr, "Erro: O corpo da resposta não corresponde ao padrão '%s'\n", p.BodyRegex)
+				assertionsPassed = false
+			} else {
+				p.logf(os.Stdout, "Sucesso: O corpo da resposta corresponde ao padrão '%s'\n", p.BodyRegex)
+			}
+		}
+
+		if p.BodyJSONPath != "" {
+			expr, expected, found := strings.Cut(p.BodyJSONPath, "=")
+			if !found {
+				result.Error = "--body-jsonpath precisa do formato <expr>=<esperado>"
+				return result
+			}
+			var decoded interface{}
+			if jsonErr := json.Unmarshal(bodyBytes, &decoded); jsonErr != nil {
+				p.logf(os.Stderr, "Erro ao interpretar o corpo como JSON: %v\n", jsonErr)
+				assertionsPassed = false
+			} else if actual, pathErr := evaluateJSONPath(decoded, expr); pathErr != nil {
+				p.logf(os.Stderr, "Erro: --body-jsonpath '%s' falhou: %v\n", expr, pathErr)
+				assertionsPassed = false
+			} else if jsonValueToString(actual) != expected {
+				p.logf(os.Stderr, "Erro: --body-jsonpath '%s' é '%s', esperado '%s'\n", expr, jsonValueToString(actual), expected)
+				assertionsPassed = false
+			} else {
+				p.logf(os.Stdout, "Sucesso: --body-jsonpath '%s' é '%s'\n", expr, expected)
+			}
+		}
+	}
+
+	for _, h := range p.HeaderEquals {
+		name, expected, found := strings.Cut(h, ":")
+		if !found {
+			result.Error = fmt.Sprintf("--header-equals precisa do formato \"Nome: valor\": %s", h)
+			return result
+		}
+		name, expected = strings.TrimSpace(name), strings.TrimSpace(expected)
+		actual := resp.Header.Get(name)
+		if actual != expected {
+			p.logf(os.Stderr, "Erro: Cabeçalho '%s' é '%s', esperado '%s'\n", name, actual, expected)
+			assertionsPassed = false
+		} else {
+			p.logf(os.Stdout, "Sucesso: Cabeçalho '%s' é '%s'\n", name, expected)
+		}
+	}
+
+	for _, h := range p.HeaderRegex {
+		name, pattern, found := strings.Cut(h, ":")
+		if !found {
+			result.Error = fmt.Sprintf("--header-regex precisa do formato \"Nome: padrão\": %s", h)
+			return result
+		}
+		name, pattern = strings.TrimSpace(name), strings.TrimSpace(pattern)
+		headerRegex, compileErr := regexp.Compile(pattern)
+		if compileErr != nil {
+			result.Error = fmt.Sprintf("--header-regex inválido '%s': %v", pattern, compileErr)
+			return result
+		}
+		actual := resp.Header.Get(name)
+		if !headerRegex.MatchString(actual) {
+			p.logf(os.Stderr, "Erro: Cabeçalho '%s' ('%s') não corresponde ao padrão '%s'\n", name, actual, pattern)
+			assertionsPassed = false
+		} else {
+			p.logf(os.Stdout, "Sucesso: Cabeçalho '%s' corresponde ao padrão '%s'\n", name, pattern)
+		}
+	}
+
+	if hasAssertion && !assertionsPassed {
+		return result
+	}
+
+	if p.AcceptedCodeMap != nil {
+		if p.AcceptedCodeMap[resp.StatusCode] {
+			p.logf(os.Stdout, "Pedido bem-sucedido para %s (%s). Código de estado: %d (aceite)\n", p.URL, p.Method, resp.StatusCode)
+			result.Success = true
+			return result
+		}
+		p.logf(os.Stdout, "Erro no pedido para %s (%s). Código de estado: %d (não aceite, esperado: %s)\n", p.URL, p.Method, resp.StatusCode, p.AcceptedCodesDisplay)
+		return result
+	}
+
+	// Sem códigos esperados: sucesso se não há asserções, ou se todas as asserções passaram
+	p.logf(os.Stdout, "Pedido bem-sucedido para %s (%s). Código de estado: %d (nenhum código esperado)\n", p.URL, p.Method, resp.StatusCode)
+	result.Success = true
+	return result
+}
+
+// evaluateJSONPath avalia um subconjunto simples de JSONPath ($.a.b[0].c) sobre
+// um valor decodificado de encoding/json.
+func evaluateJSONPath(data interface{}, expr string) (interface{}, error) {
+	expr = strings.TrimPrefix(expr, "$")
+	expr = strings.TrimPrefix(expr, ".")
+	if expr == "" {
+		return data, nil
+	}
+
+	current := data
+	for _, rawSeg := range strings.Split(expr, ".") {
+		seg := rawSeg
+		for seg != "" {
+			if idx := strings.IndexByte(seg, '['); idx >= 0 {
+				name := seg[:idx]
+				if name != "" {
+					m, ok := current.(map[string]interface{})
+					if !ok {
+						return nil, fmt.Errorf("'%s' não é um objeto", name)
+					}
+					current, ok = m[name]
+					if !ok {
+						return nil, fmt.Errorf("campo '%s' não encontrado", name)
+					}
+				}
+				closeIdx := strings.IndexByte(seg[idx:], ']')
+				if closeIdx < 0 {
+					return nil, fmt.Errorf("índice malformado em '%s'", rawSeg)
+				}
+				closeIdx += idx
+				n, err := strconv.Atoi(seg[idx+1 : closeIdx])
+				if err != nil {
+					return nil, fmt.Errorf("índice inválido em '%s'", rawSeg)
+				}
+				arr, ok := current.([]interface{})
+				if !ok || n < 0 || n >= len(arr) {
+					return nil, fmt.Errorf("índice %d fora dos limites em '%s'", n, rawSeg)
+				}
+				current = arr[n]
+				seg = seg[closeIdx+1:]
+			} else {
+				m, ok := current.(map[string]interface{})
+				if !ok {
+					return nil, fmt.Errorf("'%s' não é um objeto", seg)
+				}
+				current, ok = m[seg]
+				if !ok {
+					return nil, fmt.Errorf("campo '%s' não encontrado", seg)
+				}
+				seg = ""
+			}
+		}
+	}
+	return current, nil
+}
+
+// jsonValueToString converte um valor JSON decodificado para a sua representação textual,
+// usada para comparar com o <esperado> de --body-jsonpath.
+func jsonValueToString(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// parseRetryOn interpreta a lista --retry-on, devolvendo o conjunto de códigos
+// explícitos e as flags para os tokens especiais "5xx", "connect" e "timeout".
+// Um token inválido é devolvido como erro — nunca termina o processo, para que
+// o chamador (modo único ou modo batch) decida como reportar a falha.
+func parseRetryOn(retryOnStr string) (codes map[int]bool, retry5xx, retryOnConnect, retryOnTimeout bool, err error) {
+	codes = make(map[int]bool)
+	for _, tok := range strings.Split(retryOnStr, ",") {
+		tok = strings.TrimSpace(tok)
+		switch tok {
+		case "":
+			continue
+		case "5xx":
+			retry5xx = true
+		case "connect":
+			retryOnConnect = true
+		case "timeout":
+			retryOnTimeout = true
+		default:
+			code, convErr := strconv.Atoi(tok)
+			if convErr != nil {
+				return nil, false, false, false, fmt.Errorf("--retry-on contém um valor inválido: %s", tok)
+			}
+			codes[code] = true
+		}
+	}
+	return codes, retry5xx, retryOnConnect, retryOnTimeout, nil
+}
+
+// isConnectError identifica falhas de rede ao nível da ligação (ex: recusada, DNS).
+func isConnectError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr)
+}
+
+// isTimeoutError identifica timeouts, quer do orçamento total quer do cliente HTTP.
+func isTimeoutError(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// backoffDelay calcula o atraso exponencial com full jitter para a tentativa indicada.
+func backoffDelay(attempt, baseMs, maxMs int) int {
+	delay := baseMs
+	for i := 0; i < attempt; i++ {
+		delay *= 2
+		if delay > maxMs {
+			delay = maxMs
+			break
+		}
+	}
+	if delay > maxMs {
+		delay = maxMs
+	}
+	jitter := 0
+	if delay > 0 {
+		jitter = rand.Intn(delay/2 + 1)
+	}
+	return delay + jitter
+}
+
+// attemptOutcome descreve, de forma resumida, o resultado de uma tentativa para os logs verbose.
+func attemptOutcome(resp *http.Response, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return fmt.Sprintf("código de estado %d", resp.StatusCode)
+}
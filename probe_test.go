@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestBackoffDelay(t *testing.T) {
+	cases := []struct {
+		name          string
+		attempt       int
+		baseMs, maxMs int
+		wantMin       int
+		wantMaxNoJit  int
+	}{
+		{"tentativa 0 parte da base", 0, 200, 2000, 200, 200},
+		{"duplica a cada tentativa", 1, 200, 2000, 400, 400},
+		{"duplica novamente", 2, 200, 2000, 800, 800},
+		{"satura no máximo", 5, 200, 2000, 2000, 2000},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// O jitter acrescenta até metade do atraso base, por isso
+			// verificamos o intervalo em vez de um valor exato.
+			delay := backoffDelay(c.attempt, c.baseMs, c.maxMs)
+			maxWithJitter := c.wantMaxNoJit + c.wantMaxNoJit/2 + 1
+			if delay < c.wantMin || delay > maxWithJitter {
+				t.Errorf("backoffDelay(%d, %d, %d) = %d, fora do intervalo [%d, %d]", c.attempt, c.baseMs, c.maxMs, delay, c.wantMin, maxWithJitter)
+			}
+		})
+	}
+}
+
+func TestBackoffDelayNeverExceedsMax(t *testing.T) {
+	for attempt := 0; attempt < 20; attempt++ {
+		delay := backoffDelay(attempt, 100, 1000)
+		if delay > 1000+1000/2+1 {
+			t.Errorf("backoffDelay(%d, 100, 1000) = %d, excede o máximo com jitter", attempt, delay)
+		}
+	}
+}
+
+func TestEvaluateJSONPath(t *testing.T) {
+	const body = `{"a":{"b":[{"c":"valor"},{"c":"outro"}]},"n":42}`
+
+	var decoded interface{}
+	if err := json.Unmarshal([]byte(body), &decoded); err != nil {
+		t.Fatalf("falha ao preparar o JSON de teste: %v", err)
+	}
+
+	cases := []struct {
+		name    string
+		expr    string
+		want    string
+		wantErr bool
+	}{
+		{"caminho simples com índice", "$.a.b[0].c", "valor", false},
+		{"outro índice do array", "$.a.b[1].c", "outro", false},
+		{"campo numérico na raiz", "$.n", "42", false},
+		{"campo inexistente", "$.a.x", "", true},
+		{"índice fora dos limites", "$.a.b[5].c", "", true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual, err := evaluateJSONPath(decoded, c.expr)
+			if c.wantErr {
+				if err == nil {
+					t.Errorf("evaluateJSONPath(%q) esperava erro, obteve %v", c.expr, actual)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("evaluateJSONPath(%q) erro inesperado: %v", c.expr, err)
+			}
+			if got := jsonValueToString(actual); got != c.want {
+				t.Errorf("evaluateJSONPath(%q) = %q, esperado %q", c.expr, got, c.want)
+			}
+		})
+	}
+}